@@ -0,0 +1,143 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// cachingProvider wraps an `AddressProvider`, serving repeat queries from a
+// bounded, TTL-based LRU cache and coalescing concurrent lookups for the
+// same key into a single upstream call.
+type cachingProvider struct {
+	upstream AddressProvider
+	cache    *lru
+	group    singleflight.Group
+	ttl      time.Duration
+}
+
+// newCachingProvider wraps upstream with an in-memory cache of up to size
+// entries, each valid for ttl. If store is non-nil, cache entries are also
+// persisted there and used to repopulate the in-memory cache on startup.
+func newCachingProvider(upstream AddressProvider, size int, ttl time.Duration, store *boltStore) (*cachingProvider, error) {
+	p := &cachingProvider{
+		upstream: upstream,
+		cache:    newLRU(size),
+		ttl:      ttl,
+	}
+
+	if store != nil {
+		p.cache.store = store
+
+		if err := store.pruneExpired(ttl); err != nil {
+			return nil, fmt.Errorf("failed to prune expired cache entries: %w", err)
+		}
+
+		entries, err := store.loadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cache from disk: %w", err)
+		}
+		for key, entry := range entries {
+			if !entry.expired(ttl) {
+				p.cache.add(key, entry)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// LookupByPostalCode implements `AddressProvider`.
+func (p *cachingProvider) LookupByPostalCode(ctx context.Context, postalCode, houseNumber, houseLetter string) ([]Address, error) {
+	key := postalCodeCacheKey(postalCode, houseNumber, houseLetter)
+
+	if entry, ok := p.cache.get(key); ok && !entry.expired(p.ttl) {
+		recordCacheResult(true)
+		return entry.addresses, nil
+	}
+	recordCacheResult(false)
+
+	v, err, _ := p.group.Do(key, func() (interface{}, error) {
+		addresses, err := p.upstream.LookupByPostalCode(ctx, postalCode, houseNumber, houseLetter)
+		if err != nil {
+			return nil, err
+		}
+		p.cache.set(key, cacheEntry{addresses: addresses, storedAt: time.Now()})
+		return addresses, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.([]Address), nil
+}
+
+// NearestByCoordinates implements `AddressProvider`.
+func (p *cachingProvider) NearestByCoordinates(ctx context.Context, latitude, longitude float64) (*Address, error) {
+	key := coordinatesCacheKey(latitude, longitude)
+
+	if entry, ok := p.cache.get(key); ok && !entry.expired(p.ttl) {
+		recordCacheResult(true)
+		return entry.address, nil
+	}
+	recordCacheResult(false)
+
+	v, err, _ := p.group.Do(key, func() (interface{}, error) {
+		addr, err := p.upstream.NearestByCoordinates(ctx, latitude, longitude)
+		if err != nil {
+			return nil, err
+		}
+		p.cache.set(key, cacheEntry{address: addr, storedAt: time.Now()})
+		return addr, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*Address), nil
+}
+
+// AddressesWithinBBox forwards to the upstream provider's bounding-box
+// query, if it implements one. Bounding-box results are not cached, since
+// they're only used for the broader `nearestAddresses` sweep.
+func (p *cachingProvider) AddressesWithinBBox(ctx context.Context, minLat, minLon, maxLat, maxLon float64) ([]Address, error) {
+	bp, ok := p.upstream.(bboxProvider)
+	if !ok {
+		return nil, errBBoxUnsupported
+	}
+
+	return bp.AddressesWithinBBox(ctx, minLat, minLon, maxLat, maxLon)
+}
+
+// postalCodeCacheKey builds a normalized cache key for a postal code query.
+func postalCodeCacheKey(postalCode, houseNumber, houseLetter string) string {
+	postalCode = strings.ToUpper(strings.ReplaceAll(postalCode, " ", ""))
+	return fmt.Sprintf("pc:%s:%s:%s", postalCode, houseNumber, strings.ToUpper(houseLetter))
+}
+
+// coordinatesCacheKey builds a normalized cache key for a coordinates query,
+// rounding to ~1m precision so nearby probes share a cache entry.
+func coordinatesCacheKey(latitude, longitude float64) string {
+	return fmt.Sprintf("ll:%s:%s",
+		strconv.FormatFloat(latitude, 'f', 5, 64),
+		strconv.FormatFloat(longitude, 'f', 5, 64),
+	)
+}
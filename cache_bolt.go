@@ -0,0 +1,155 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// cacheBucket is the BoltDB bucket holding persisted cache entries.
+var cacheBucket = []byte("cache")
+
+// boltStore persists cache entries to a BoltDB file, so the cache survives
+// process restarts.
+type boltStore struct {
+	db *bbolt.DB
+}
+
+// openBoltStore opens (creating if necessary) a BoltDB file at path for use
+// as a cache store.
+func openBoltStore(path string) (*boltStore, error) {
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache database: %w", err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(cacheBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create cache bucket: %w", err)
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
+
+// persistedEntry is the on-disk representation of a cacheEntry.
+type persistedEntry struct {
+	Addresses []Address `json:"addresses,omitempty"`
+	Address   *Address  `json:"address,omitempty"`
+	StoredAt  time.Time `json:"storedAt"`
+}
+
+// save persists entry under key.
+func (s *boltStore) save(key string, entry cacheEntry) error {
+	data, err := json.Marshal(persistedEntry{
+		Addresses: entry.addresses,
+		Address:   entry.address,
+		StoredAt:  entry.storedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(cacheBucket).Put([]byte(key), data)
+	})
+}
+
+// loadAll reads every persisted entry back into memory.
+func (s *boltStore) loadAll() (map[string]cacheEntry, error) {
+	entries := make(map[string]cacheEntry)
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(cacheBucket).ForEach(func(k, v []byte) error {
+			var p persistedEntry
+			if err := json.Unmarshal(v, &p); err != nil {
+				return fmt.Errorf("failed to unmarshal cache entry for key %q: %w", k, err)
+			}
+
+			entries[string(k)] = cacheEntry{
+				addresses: p.Addresses,
+				address:   p.Address,
+				storedAt:  p.StoredAt,
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// delete removes the persisted entry for key, if any. It is used to
+// propagate LRU evictions to disk, so the store stays bounded to roughly
+// the in-memory cache's contents. Expired entries are removed separately,
+// by pruneExpired on startup.
+func (s *boltStore) delete(key string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(cacheBucket).Delete([]byte(key))
+	})
+}
+
+// pruneExpired deletes every persisted entry older than ttl. A zero ttl is
+// a no-op, matching cacheEntry.expired's "never expire" semantics.
+func (s *boltStore) pruneExpired(ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+
+	var expired [][]byte
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(cacheBucket).ForEach(func(k, v []byte) error {
+			var p persistedEntry
+			if err := json.Unmarshal(v, &p); err != nil {
+				return fmt.Errorf("failed to unmarshal cache entry for key %q: %w", k, err)
+			}
+
+			if (cacheEntry{storedAt: p.StoredAt}).expired(ttl) {
+				expired = append(expired, append([]byte(nil), k...))
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(cacheBucket)
+		for _, k := range expired {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// outputFormat is the shape of an `Address` rendered by a tool's `format`
+// argument.
+type outputFormat string
+
+const (
+	formatText    outputFormat = "text"
+	formatJSON    outputFormat = "json"
+	formatGeoJSON outputFormat = "geojson"
+	formatCompact outputFormat = "compact"
+)
+
+// geoJSONFeatureCollection is a minimal GeoJSON `FeatureCollection` of
+// `Point` geometries in WGS84, as produced by `formatAddressesGeoJSON`.
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONPoint           `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// formatAddresses renders addresses in the given format. For "text" and
+// "compact", each address is rendered on its own line/block, separated by
+// newlines; "json" renders the raw `Address` slice; "geojson" renders a
+// `FeatureCollection`.
+func formatAddresses(addresses []Address, format outputFormat) (string, error) {
+	switch format {
+	case "", formatText:
+		var sb strings.Builder
+		for _, addr := range addresses {
+			sb.WriteString(formatAddress(addr))
+		}
+		return sb.String(), nil
+
+	case formatCompact:
+		lines := make([]string, len(addresses))
+		for i, addr := range addresses {
+			lines[i] = formatAddressCompact(addr)
+		}
+		return strings.Join(lines, "\n"), nil
+
+	case formatJSON:
+		data, err := json.MarshalIndent(addresses, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal addresses as JSON: %w", err)
+		}
+		return string(data), nil
+
+	case formatGeoJSON:
+		return formatAddressesGeoJSON(addresses)
+
+	default:
+		return "", fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// formatAddressCompact renders addr as a single line: "street
+// housenumber, postcode city".
+func formatAddressCompact(addr Address) string {
+	houseNumber := strconv.Itoa(addr.HouseNumber)
+	if addr.HouseLetter != "" {
+		houseNumber += addr.HouseLetter
+	}
+	if addr.HouseSuffix != "" {
+		houseNumber += "-" + addr.HouseSuffix
+	}
+
+	return fmt.Sprintf("%s %s, %s %s", addr.Street, houseNumber, addr.PostalCode, addr.City)
+}
+
+// formatAddressesGeoJSON renders addresses as a GeoJSON `FeatureCollection`
+// of `Point` geometries in WGS84, carrying street/house/city/build-year in
+// each feature's properties.
+func formatAddressesGeoJSON(addresses []Address) (string, error) {
+	fc := geoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]geoJSONFeature, len(addresses)),
+	}
+
+	for i, addr := range addresses {
+		fc.Features[i] = geoJSONFeature{
+			Type: "Feature",
+			Geometry: geoJSONPoint{
+				Type:        "Point",
+				Coordinates: []float64{addr.Longitude, addr.Latitude},
+			},
+			Properties: map[string]interface{}{
+				"street":      addr.Street,
+				"houseNumber": addr.HouseNumber,
+				"houseLetter": addr.HouseLetter,
+				"houseSuffix": addr.HouseSuffix,
+				"city":        addr.City,
+				"postalCode":  addr.PostalCode,
+				"buildYear":   addr.BuildYear,
+			},
+		}
+	}
+
+	data, err := json.MarshalIndent(fc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal addresses as GeoJSON: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "math"
+
+// earthRadiusMeters is the mean radius of the Earth, used for great-circle
+// distance calculations.
+const earthRadiusMeters = 6371000.0
+
+// haversineMeters returns the great-circle distance in meters between two
+// WGS84 coordinates.
+func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	phi1, phi2 := rad(lat1), rad(lat2)
+	dPhi := rad(lat2 - lat1)
+	dLambda := rad(lon2 - lon1)
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return earthRadiusMeters * c
+}
+
+// metersToDegreesLat converts a distance in meters to an approximate delta
+// in degrees of latitude.
+func metersToDegreesLat(meters float64) float64 {
+	return meters / 111320.0
+}
+
+// metersToDegreesLon converts a distance in meters to an approximate delta
+// in degrees of longitude at the given latitude.
+func metersToDegreesLon(meters, latitude float64) float64 {
+	return meters / (111320.0 * math.Cos(latitude*math.Pi/180))
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"os"
+)
+
+// logger is the structured logger used throughout the server. It's
+// initialized as a package-level variable, before `main` runs, so it's safe
+// to use from package-level code running during flag parsing.
+var logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+// hashPostalCode returns a short, non-reversible hash of a postal code,
+// suitable for correlating log lines without logging the address itself.
+func hashPostalCode(postalCode string) string {
+	sum := sha256.Sum256([]byte(postalCode))
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cacheEntry holds a cached lookup result, either a list of addresses (for
+// postal code queries) or a single address (for coordinate queries).
+type cacheEntry struct {
+	addresses []Address
+	address   *Address
+	storedAt  time.Time
+}
+
+// expired reports whether the entry is older than ttl. A zero ttl means
+// entries never expire.
+func (e cacheEntry) expired(ttl time.Duration) bool {
+	return ttl > 0 && time.Since(e.storedAt) > ttl
+}
+
+// lru is a fixed-size, least-recently-used cache of cacheEntry values,
+// optionally backed by an on-disk store.
+type lru struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+	store *boltStore
+}
+
+type lruItem struct {
+	key   string
+	entry cacheEntry
+}
+
+// newLRU constructs an `lru` holding up to size entries. A non-positive
+// size disables eviction.
+func newLRU(size int) *lru {
+	return &lru{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// get returns the entry for key, if present, and marks it most recently
+// used.
+func (c *lru) get(key string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+
+	c.ll.MoveToFront(elem)
+
+	return elem.Value.(*lruItem).entry, true
+}
+
+// add inserts entry for key without persisting it, used to repopulate the
+// cache from disk on startup.
+func (c *lru) add(key string, entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.insertLocked(key, entry)
+}
+
+// set inserts entry for key and, if a store is configured, persists it. If
+// the insert evicts an older entry, that entry is also removed from the
+// store so it doesn't outlive the in-memory cache.
+func (c *lru) set(key string, entry cacheEntry) {
+	c.mu.Lock()
+	evicted, evictedOK := c.insertLocked(key, entry)
+	store := c.store
+	c.mu.Unlock()
+
+	if store == nil {
+		return
+	}
+
+	if evictedOK {
+		// Eviction is best-effort; a stale disk entry only costs space, and
+		// pruneExpired already removes expired entries on startup.
+		_ = store.delete(evicted)
+	}
+
+	if err := store.save(key, entry); err != nil {
+		// Persistence is best-effort; the in-memory cache remains
+		// authoritative for this process's lifetime.
+		return
+	}
+}
+
+// insertLocked inserts entry for key, evicting the least-recently-used
+// entry if the cache is now over size. It reports the evicted key, if any.
+func (c *lru) insertLocked(key string, entry cacheEntry) (evicted string, ok bool) {
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*lruItem).entry = entry
+		c.ll.MoveToFront(elem)
+		return "", false
+	}
+
+	elem := c.ll.PushFront(&lruItem{key: key, entry: entry})
+	c.items[key] = elem
+
+	if c.size > 0 && c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			evictedKey := oldest.Value.(*lruItem).key
+			delete(c.items, evictedKey)
+			return evictedKey, true
+		}
+	}
+
+	return "", false
+}
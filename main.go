@@ -16,11 +16,9 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
-	"log"
 	"net"
 	"net/http"
 	"net/url"
@@ -32,13 +30,9 @@ import (
 	"time"
 
 	"github.com/dstotijn/go-mcp"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-// baseURL is the base URL for Bert Hubert's testing instance of his
-// `bagserv` web service.
-// See: https://berthub.eu/articles/posts/dutch-postcode-and-building-database/
-const baseURL = "https://berthub.eu/pcode"
-
 // Address represents a Dutch address with postal code information.
 type Address struct {
 	Street        string   `json:"straat"`
@@ -55,20 +49,58 @@ type Address struct {
 	Longitude     float64  `json:"lon,omitempty"`
 	X             string   `json:"x,omitempty"`
 	Y             string   `json:"y,omitempty"`
+
+	// Metadata holds provider-specific identifiers (e.g. BAG
+	// verblijfsobject/nummeraanduiding IDs) that don't have an equivalent
+	// across every `AddressProvider`.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 var (
-	httpAddr string
-	useStdio bool
-	useSSE   bool
+	httpAddr     string
+	useStdio     bool
+	useSSE       bool
+	metricsAddr  string
+	providerName string
+	cacheTTL     time.Duration
+	cacheSize    int
+	cachePath    string
 )
 
 func main() {
 	flag.StringVar(&httpAddr, "http", ":8080", "Listen address for JSON-RPC over HTTP")
 	flag.BoolVar(&useStdio, "stdio", true, "Enable stdio transport")
 	flag.BoolVar(&useSSE, "sse", false, "Enable SSE transport")
+	flag.StringVar(&metricsAddr, "metrics-addr", ":9090", "Listen address for the Prometheus /metrics endpoint (always started, independent of -sse)")
+	flag.StringVar(&providerName, "provider", "bagserv", "Address data source: \"bagserv\", \"pdok\", or \"chain\"")
+	flag.DurationVar(&cacheTTL, "cache-ttl", 1*time.Hour, "How long cached lookups remain valid (0 disables expiry)")
+	flag.IntVar(&cacheSize, "cache-size", 10000, "Maximum number of entries kept in the in-memory lookup cache")
+	flag.StringVar(&cachePath, "cache-path", "", "Optional path to a BoltDB file for persisting the lookup cache across restarts")
 	flag.Parse()
 
+	provider, err := newAddressProvider(providerName)
+	if err != nil {
+		logger.Error("Failed to initialize address provider", "error", err)
+		os.Exit(1)
+	}
+	provider = newCompletingProvider(provider)
+
+	var cacheStore *boltStore
+	if cachePath != "" {
+		cacheStore, err = openBoltStore(cachePath)
+		if err != nil {
+			logger.Error("Failed to open cache store", "error", err)
+			os.Exit(1)
+		}
+		defer cacheStore.Close()
+	}
+
+	provider, err = newCachingProvider(provider, cacheSize, cacheTTL, cacheStore)
+	if err != nil {
+		logger.Error("Failed to initialize lookup cache", "error", err)
+		os.Exit(1)
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
@@ -87,7 +119,8 @@ func main() {
 		host := "localhost"
 		hostPart, port, err := net.SplitHostPort(httpAddr)
 		if err != nil {
-			log.Fatalf("Failed to split host and port: %v", err)
+			logger.Error("Failed to split host and port", "error", err)
+			os.Exit(1)
 		}
 
 		if hostPart != "" {
@@ -103,30 +136,56 @@ func main() {
 	}
 
 	mcpServer := mcp.NewServer(mcp.ServerConfig{}, opts...)
-	registerPostalCodeTools(mcpServer)
+	registerPostalCodeTools(mcpServer, provider)
 
 	mcpServer.Start(ctx)
 
-	httpServer := &http.Server{
-		Addr:    httpAddr,
-		Handler: mcpServer,
-		BaseContext: func(l net.Listener) context.Context {
-			return ctx
-		},
-	}
-
+	var httpServer *http.Server
 	if useSSE {
+		mux := http.NewServeMux()
+		mux.Handle("/", mcpServer)
+
+		httpServer = &http.Server{
+			Addr:    httpAddr,
+			Handler: mux,
+			BaseContext: func(l net.Listener) context.Context {
+				return ctx
+			},
+		}
+
 		go func() {
 			if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-				log.Fatalf("HTTP server error: %v", err)
+				logger.Error("HTTP server error", "error", err)
+				os.Exit(1)
 			}
 		}()
 	}
 
-	log.Printf("MCP server started, using transports: %v", transports)
+	// The metrics endpoint is started regardless of transport, so operators
+	// have visibility into the default stdio launch mode too.
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+
+	metricsServer := &http.Server{
+		Addr:    metricsAddr,
+		Handler: metricsMux,
+		BaseContext: func(l net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("Metrics server error", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	logger.Info("MCP server started", "transports", transports)
 	if useSSE {
-		log.Printf("SSE transport endpoint: %v", sseURL.String())
+		logger.Info("SSE transport endpoint", "url", sseURL.String())
 	}
+	logger.Info("Metrics endpoint listening", "addr", metricsAddr)
 
 	// Wait for interrupt signal.
 	<-ctx.Done()
@@ -137,7 +196,7 @@ func main() {
 	cancelContext, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	log.Printf("Shutting down server (waiting %s)... Press Ctrl+C to force quit.", timeout)
+	logger.Info("Shutting down server, press Ctrl+C to force quit", "timeout", timeout)
 
 	var wg sync.WaitGroup
 
@@ -146,37 +205,68 @@ func main() {
 		go func() {
 			defer wg.Done()
 			if err := httpServer.Shutdown(cancelContext); err != nil && !errors.Is(err, context.DeadlineExceeded) {
-				log.Printf("HTTP server shutdown error: %v", err)
+				logger.Error("HTTP server shutdown error", "error", err)
 			}
 		}()
 	}
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := metricsServer.Shutdown(cancelContext); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			logger.Error("Metrics server shutdown error", "error", err)
+		}
+	}()
+
 	wg.Wait()
 }
 
-// registerPostalCodeTools registers the tools for postal code lookup.
-func registerPostalCodeTools(mcpServer *mcp.Server) {
+// registerPostalCodeTools registers the tools for postal code lookup,
+// backed by the given `AddressProvider`.
+func registerPostalCodeTools(mcpServer *mcp.Server, provider AddressProvider) {
 	// Define the arguments for the `lookup_by_postal_code` tool.
 	type lookupByPostalCodeArgs struct {
 		PostalCode  string `json:"postalCode"`
 		HouseNumber string `json:"houseNumber,omitempty"` // `omitempty` will make this prop optional in the JSON Schema.
 		HouseLetter string `json:"houseLetter,omitempty"` // `omitempty` will make this prop optional in the JSON Schema.
+		Format      string `json:"format,omitempty"`      // One of "text" (default), "json", "geojson", or "compact".
 	}
 
 	// Define the arguments for the `lookup_by_coordinates` tool.
 	type lookupByCoordinatesArgs struct {
 		Latitude  float64 `json:"latitude"`
 		Longitude float64 `json:"longitude"`
+		Format    string  `json:"format,omitempty"` // One of "text" (default), "json", "geojson", or "compact".
+	}
+
+	// Define the arguments for the `lookup_nearest` tool.
+	type lookupNearestArgs struct {
+		Latitude     float64 `json:"latitude"`
+		Longitude    float64 `json:"longitude"`
+		RadiusMeters float64 `json:"radiusMeters,omitempty"` // `omitempty` will make this prop optional in the JSON Schema.
+		Limit        int     `json:"limit,omitempty"`        // `omitempty` will make this prop optional in the JSON Schema.
+	}
+
+	// Define the arguments for the `convert_coordinates` tool. Exactly one
+	// of (Latitude, Longitude) or (X, Y) must be given; the other pair is
+	// computed and returned.
+	type convertCoordinatesArgs struct {
+		Latitude  float64 `json:"latitude,omitempty"`
+		Longitude float64 `json:"longitude,omitempty"`
+		X         float64 `json:"x,omitempty"`
+		Y         float64 `json:"y,omitempty"`
 	}
 
 	mcpServer.RegisterTools(mcp.CreateTool(mcp.ToolDef[lookupByPostalCodeArgs]{
 		Name:        "lookup_by_postal_code",
 		Description: "Look up Dutch addresses by postal code and optional house number and letter.",
-		HandleFunc: func(ctx context.Context, args lookupByPostalCodeArgs) *mcp.CallToolResult {
+		HandleFunc: instrumentHandler("lookup_by_postal_code", func(ctx context.Context, args lookupByPostalCodeArgs) *mcp.CallToolResult {
 			cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 			defer cancel()
 
-			addresses, err := lookupByPostalCode(cctx, args.PostalCode, args.HouseNumber, args.HouseLetter)
+			logger.Info("lookup_by_postal_code request", "postalCodeHash", hashPostalCode(args.PostalCode))
+
+			addresses, err := provider.LookupByPostalCode(cctx, args.PostalCode, args.HouseNumber, args.HouseLetter)
 			if err != nil {
 				return &mcp.CallToolResult{
 					Content: []mcp.Content{
@@ -198,28 +288,34 @@ func registerPostalCodeTools(mcpServer *mcp.Server) {
 				}
 			}
 
-			var contents []mcp.Content
-
-			for _, addr := range addresses {
-				contents = append(contents, mcp.TextContent{
-					Text: formatAddress(addr),
-				})
+			text, err := formatAddresses(addresses, outputFormat(args.Format))
+			if err != nil {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						mcp.TextContent{
+							Text: fmt.Sprintf("Error formatting addresses: %v", err),
+						},
+					},
+					IsError: true,
+				}
 			}
 
 			return &mcp.CallToolResult{
-				Content: contents,
+				Content: []mcp.Content{
+					mcp.TextContent{Text: text},
+				},
 			}
-		},
+		}),
 	}))
 
 	mcpServer.RegisterTools(mcp.CreateTool(mcp.ToolDef[lookupByCoordinatesArgs]{
 		Name:        "lookup_by_coordinates",
 		Description: "Look up the nearest Dutch address by WGS84 (GPS) coordinates.",
-		HandleFunc: func(ctx context.Context, args lookupByCoordinatesArgs) *mcp.CallToolResult {
+		HandleFunc: instrumentHandler("lookup_by_coordinates", func(ctx context.Context, args lookupByCoordinatesArgs) *mcp.CallToolResult {
 			cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 			defer cancel()
 
-			address, err := lookupByCoordinates(cctx, args.Latitude, args.Longitude)
+			address, err := provider.NearestByCoordinates(cctx, args.Latitude, args.Longitude)
 			if err != nil {
 				return &mcp.CallToolResult{
 					Content: []mcp.Content{
@@ -241,86 +337,113 @@ func registerPostalCodeTools(mcpServer *mcp.Server) {
 				}
 			}
 
+			text, err := formatAddresses([]Address{*address}, outputFormat(args.Format))
+			if err != nil {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						mcp.TextContent{
+							Text: fmt.Sprintf("Error formatting address: %v", err),
+						},
+					},
+					IsError: true,
+				}
+			}
+
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{
-					mcp.TextContent{
-						Text: formatAddress(*address),
-					},
+					mcp.TextContent{Text: text},
 				},
 			}
-		},
+		}),
 	}))
-}
 
-// lookupByPostalCode looks up addresses by postal code and optional house number and letter.
-func lookupByPostalCode(ctx context.Context, postalCode, houseNumber, houseLetter string) ([]Address, error) {
-	// Normalize postal code (remove spaces).
-	postalCode = strings.ReplaceAll(postalCode, " ", "")
-
-	requestURL := fmt.Sprintf("%v/%v", baseURL, postalCode)
-	if houseNumber != "" {
-		requestURL += fmt.Sprintf("/%v", houseNumber)
-		if houseLetter != "" {
-			requestURL += fmt.Sprintf("/%v", houseLetter)
-		}
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status code %d", resp.StatusCode)
-	}
-
-	var addresses []Address
-	if err := json.NewDecoder(resp.Body).Decode(&addresses); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
-	}
+	mcpServer.RegisterTools(mcp.CreateTool(mcp.ToolDef[lookupNearestArgs]{
+		Name:        "lookup_nearest",
+		Description: "Look up Dutch addresses within a radius of WGS84 (GPS) coordinates, sorted by distance.",
+		HandleFunc: instrumentHandler("lookup_nearest", func(ctx context.Context, args lookupNearestArgs) *mcp.CallToolResult {
+			cctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
 
-	for i := range addresses {
-		addresses[i].PostalCode = postalCode
-	}
+			radiusMeters := args.RadiusMeters
+			if radiusMeters <= 0 {
+				radiusMeters = 250
+			}
 
-	return addresses, nil
-}
+			limit := args.Limit
+			if limit <= 0 {
+				limit = 10
+			}
 
-// lookupByCoordinates looks up the nearest address by WGS84 (GPS) coordinates.
-func lookupByCoordinates(ctx context.Context, latitude, longitude float64) (*Address, error) {
-	requestURL := fmt.Sprintf("%v/%v/%v", baseURL, latitude, longitude)
+			addresses, err := nearestAddresses(cctx, provider, args.Latitude, args.Longitude, radiusMeters, limit)
+			if err != nil {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						mcp.TextContent{
+							Text: fmt.Sprintf("Error looking up nearest addresses: %v", err),
+						},
+					},
+					IsError: true,
+				}
+			}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+			if len(addresses) == 0 {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						mcp.TextContent{
+							Text: "No addresses found within the given radius.",
+						},
+					},
+				}
+			}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
+			var contents []mcp.Content
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status code %d", resp.StatusCode)
-	}
+			for _, addr := range addresses {
+				contents = append(contents, mcp.TextContent{
+					Text: formatAddress(addr),
+				})
+			}
 
-	var addresses []Address
-	if err := json.NewDecoder(resp.Body).Decode(&addresses); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
-	}
+			return &mcp.CallToolResult{
+				Content: contents,
+			}
+		}),
+	}))
 
-	if len(addresses) == 0 {
-		return nil, nil
-	}
+	mcpServer.RegisterTools(mcp.CreateTool(mcp.ToolDef[convertCoordinatesArgs]{
+		Name:        "convert_coordinates",
+		Description: "Convert between WGS84 (GPS) coordinates and the Dutch Rijksdriehoek (RD, EPSG:28992) grid. Provide either latitude/longitude or x/y.",
+		HandleFunc: instrumentHandler("convert_coordinates", func(ctx context.Context, args convertCoordinatesArgs) *mcp.CallToolResult {
+			hasWGS84 := args.Latitude != 0 || args.Longitude != 0
+			hasRD := args.X != 0 || args.Y != 0
+
+			var text string
+
+			switch {
+			case hasWGS84 && !hasRD:
+				x, y := WGS84ToRD(args.Latitude, args.Longitude)
+				text = fmt.Sprintf("RD (EPSG:28992): X %v, Y %v", x, y)
+			case hasRD && !hasWGS84:
+				lat, lon := RDToWGS84(args.X, args.Y)
+				text = fmt.Sprintf("WGS84: %v, %v", lat, lon)
+			default:
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						mcp.TextContent{
+							Text: "Provide either latitude/longitude or x/y, not both or neither.",
+						},
+					},
+					IsError: true,
+				}
+			}
 
-	return &addresses[0], nil
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{Text: text},
+				},
+			}
+		}),
+	}))
 }
 
 // formatAddress formats an address for display.
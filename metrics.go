@@ -0,0 +1,129 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/dstotijn/go-mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// metricsNamespace prefixes every metric exported by this server.
+const metricsNamespace = "mcp_dutch_postal_codes"
+
+var (
+	upstreamRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "upstream_requests_total",
+		Help:      "Number of requests made to upstream address data sources.",
+	}, []string{"provider", "status"})
+
+	upstreamRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Latency of requests made to upstream address data sources.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"provider"})
+
+	cacheResultsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "cache_results_total",
+		Help:      "Number of lookups served from, or missed by, the address cache.",
+	}, []string{"result"})
+
+	toolCallsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "tool_calls_total",
+		Help:      "Number of MCP tool calls handled, by tool name and outcome.",
+	}, []string{"tool", "outcome"})
+
+	toolCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "tool_call_duration_seconds",
+		Help:      "Latency of MCP tool calls, by tool name.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"tool"})
+)
+
+// instrumentedTransport wraps an `http.RoundTripper`, recording request
+// count and latency metrics labeled by provider name.
+type instrumentedTransport struct {
+	provider string
+	next     http.RoundTripper
+}
+
+// newInstrumentedTransport wraps next with metrics recording for the given
+// provider name. A nil next uses `http.DefaultTransport`.
+func newInstrumentedTransport(provider string, next http.RoundTripper) *instrumentedTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &instrumentedTransport{provider: provider, next: next}
+}
+
+// RoundTrip implements `http.RoundTripper`.
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	resp, err := t.next.RoundTrip(req)
+
+	upstreamRequestDuration.WithLabelValues(t.provider).Observe(time.Since(start).Seconds())
+
+	status := "error"
+	if err == nil {
+		status = http.StatusText(resp.StatusCode)
+	}
+	upstreamRequestsTotal.WithLabelValues(t.provider, status).Inc()
+
+	return resp, err
+}
+
+// recordCacheResult records a cache hit or miss.
+func recordCacheResult(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheResultsTotal.WithLabelValues(result).Inc()
+}
+
+// recordToolCall records the outcome and latency of an MCP tool call.
+func recordToolCall(tool string, start time.Time, isError bool) {
+	outcome := "success"
+	if isError {
+		outcome = "error"
+	}
+	toolCallsTotal.WithLabelValues(tool, outcome).Inc()
+	toolCallDuration.WithLabelValues(tool).Observe(time.Since(start).Seconds())
+}
+
+// instrumentHandler wraps a tool's `HandleFunc`, recording call count,
+// latency, and a structured log line for every invocation.
+func instrumentHandler[T any](tool string, handler func(context.Context, T) *mcp.CallToolResult) func(context.Context, T) *mcp.CallToolResult {
+	return func(ctx context.Context, args T) *mcp.CallToolResult {
+		start := time.Now()
+		result := handler(ctx, args)
+		duration := time.Since(start)
+
+		recordToolCall(tool, start, result.IsError)
+		logger.Info("tool call", "tool", tool, "duration", duration, "error", result.IsError)
+
+		return result
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// bboxProvider is implemented by `AddressProvider`s that can efficiently
+// return every address within a bounding box, instead of only the single
+// nearest match. `pdokProvider` implements this, and decorators such as
+// `completingProvider`/`cachingProvider` forward to it when their upstream
+// supports it.
+type bboxProvider interface {
+	AddressesWithinBBox(ctx context.Context, minLat, minLon, maxLat, maxLon float64) ([]Address, error)
+}
+
+// errBBoxUnsupported is returned by a decorator's `AddressesWithinBBox` when
+// its upstream provider doesn't implement `bboxProvider`, so callers can
+// fall back to probing instead of treating it as a hard failure.
+var errBBoxUnsupported = errors.New("upstream provider does not support bounding-box queries")
+
+// probeOffsets are relative (lat, lon) offsets, as fractions of the search
+// radius, used to probe a provider that only supports single-nearest
+// lookups for candidates around a center point.
+var probeOffsets = [][2]float64{
+	{0, 0},
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{0.7, 0.7}, {0.7, -0.7}, {-0.7, 0.7}, {-0.7, -0.7},
+}
+
+// nearestAddresses returns up to limit addresses within radiusMeters of
+// (latitude, longitude), sorted by ascending distance. If provider
+// implements `bboxProvider`, its bounding-box query is used to gather
+// candidates directly; otherwise a small grid of coordinate probes is
+// issued against `NearestByCoordinates`.
+func nearestAddresses(ctx context.Context, provider AddressProvider, latitude, longitude, radiusMeters float64, limit int) ([]Address, error) {
+	candidates, err := candidateAddresses(ctx, provider, latitude, longitude, radiusMeters)
+	if err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		addr     Address
+		distance float64
+	}
+
+	seen := make(map[string]bool, len(candidates))
+	var results []scored
+
+	for _, addr := range candidates {
+		key := addressKey(addr)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if addr.Latitude == 0 && addr.Longitude == 0 {
+			continue
+		}
+
+		distance := haversineMeters(latitude, longitude, addr.Latitude, addr.Longitude)
+		if distance > radiusMeters {
+			continue
+		}
+
+		results = append(results, scored{addr: addr, distance: distance})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].distance < results[j].distance
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	addresses := make([]Address, len(results))
+	for i, r := range results {
+		addresses[i] = r.addr
+	}
+
+	return addresses, nil
+}
+
+// candidateAddresses gathers candidate addresses around (latitude,
+// longitude), using a bounding-box query when available.
+func candidateAddresses(ctx context.Context, provider AddressProvider, latitude, longitude, radiusMeters float64) ([]Address, error) {
+	if bp, ok := provider.(bboxProvider); ok {
+		dLat := metersToDegreesLat(radiusMeters)
+		dLon := metersToDegreesLon(radiusMeters, latitude)
+
+		candidates, err := bp.AddressesWithinBBox(ctx,
+			latitude-dLat, longitude-dLon,
+			latitude+dLat, longitude+dLon,
+		)
+		if !errors.Is(err, errBBoxUnsupported) {
+			return candidates, err
+		}
+	}
+
+	var candidates []Address
+
+	for _, offset := range probeOffsets {
+		dLat := metersToDegreesLat(radiusMeters) * offset[0]
+		dLon := metersToDegreesLon(radiusMeters, latitude) * offset[1]
+
+		addr, err := provider.NearestByCoordinates(ctx, latitude+dLat, longitude+dLon)
+		if err != nil {
+			return nil, fmt.Errorf("failed to probe coordinates: %w", err)
+		}
+		if addr != nil {
+			candidates = append(candidates, *addr)
+		}
+	}
+
+	return candidates, nil
+}
+
+// addressKey returns a stable identifier for deduplicating addresses
+// returned by different probes, preferring a provider's BAG identifier when
+// available.
+func addressKey(addr Address) string {
+	if id, ok := addr.Metadata["bagVerblijfsobjectId"]; ok && id != "" {
+		return id
+	}
+	if id, ok := addr.Metadata["bagNummeraanduidingId"]; ok && id != "" {
+		return id
+	}
+
+	return fmt.Sprintf("%s|%d%s%s|%s", addr.PostalCode, addr.HouseNumber, addr.HouseLetter, addr.HouseSuffix, addr.City)
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// AddressProvider looks up Dutch addresses, either by postal code (with an
+// optional house number and letter) or by the nearest match to a WGS84
+// coordinate pair. Implementations wrap a specific upstream data source.
+type AddressProvider interface {
+	// LookupByPostalCode looks up addresses by postal code and optional
+	// house number and letter.
+	LookupByPostalCode(ctx context.Context, postalCode, houseNumber, houseLetter string) ([]Address, error)
+	// NearestByCoordinates looks up the nearest address by WGS84 (GPS)
+	// coordinates. It returns nil if no address could be found.
+	NearestByCoordinates(ctx context.Context, latitude, longitude float64) (*Address, error)
+}
+
+// newAddressProvider constructs the `AddressProvider` identified by name.
+// Supported values are "bagserv" (the default, Bert Hubert's `bagserv`
+// instance), "pdok" (the official Kadaster/PDOK Locatieserver), and "chain"
+// (tries "pdok" first, falling back to "bagserv").
+func newAddressProvider(name string) (AddressProvider, error) {
+	switch name {
+	case "", "bagserv":
+		return newBagservProvider(baseURL), nil
+	case "pdok":
+		return newPDOKProvider(pdokBaseURL), nil
+	case "chain":
+		return newChainProvider(
+			newPDOKProvider(pdokBaseURL),
+			newBagservProvider(baseURL),
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+}
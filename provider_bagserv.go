@@ -0,0 +1,115 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// baseURL is the base URL for Bert Hubert's testing instance of his
+// `bagserv` web service.
+// See: https://berthub.eu/articles/posts/dutch-postcode-and-building-database/
+const baseURL = "https://berthub.eu/pcode"
+
+// bagservProvider is an `AddressProvider` backed by the `bagserv` web
+// service.
+type bagservProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// newBagservProvider constructs an `AddressProvider` that queries a
+// `bagserv` instance at baseURL.
+func newBagservProvider(baseURL string) *bagservProvider {
+	return &bagservProvider{
+		baseURL: baseURL,
+		client: &http.Client{
+			Transport: newInstrumentedTransport("bagserv", nil),
+		},
+	}
+}
+
+// LookupByPostalCode looks up addresses by postal code and optional house
+// number and letter.
+func (p *bagservProvider) LookupByPostalCode(ctx context.Context, postalCode, houseNumber, houseLetter string) ([]Address, error) {
+	// Normalize postal code (remove spaces).
+	postalCode = strings.ReplaceAll(postalCode, " ", "")
+
+	requestURL := fmt.Sprintf("%v/%v", p.baseURL, postalCode)
+	if houseNumber != "" {
+		requestURL += fmt.Sprintf("/%v", houseNumber)
+		if houseLetter != "" {
+			requestURL += fmt.Sprintf("/%v", houseLetter)
+		}
+	}
+
+	addresses, err := p.fetchAddresses(ctx, requestURL)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range addresses {
+		addresses[i].PostalCode = postalCode
+	}
+
+	return addresses, nil
+}
+
+// NearestByCoordinates looks up the nearest address by WGS84 (GPS)
+// coordinates.
+func (p *bagservProvider) NearestByCoordinates(ctx context.Context, latitude, longitude float64) (*Address, error) {
+	requestURL := fmt.Sprintf("%v/%v/%v", p.baseURL, latitude, longitude)
+
+	addresses, err := p.fetchAddresses(ctx, requestURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	return &addresses[0], nil
+}
+
+// fetchAddresses performs a GET request against requestURL and decodes the
+// response body as a list of addresses.
+func (p *bagservProvider) fetchAddresses(ctx context.Context, requestURL string) ([]Address, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status code %d", resp.StatusCode)
+	}
+
+	var addresses []Address
+	if err := json.NewDecoder(resp.Body).Decode(&addresses); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	return addresses, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// chainProvider is an `AddressProvider` that tries a sequence of providers
+// in order, falling back to the next one if a provider returns an error or
+// no results.
+type chainProvider struct {
+	providers []AddressProvider
+}
+
+// newChainProvider constructs an `AddressProvider` that tries each of
+// providers in order, falling back to the next on failure.
+func newChainProvider(providers ...AddressProvider) *chainProvider {
+	return &chainProvider{providers: providers}
+}
+
+// LookupByPostalCode tries each underlying provider in order, returning the
+// first non-empty result.
+func (p *chainProvider) LookupByPostalCode(ctx context.Context, postalCode, houseNumber, houseLetter string) ([]Address, error) {
+	var errs []error
+
+	for _, provider := range p.providers {
+		addresses, err := provider.LookupByPostalCode(ctx, postalCode, houseNumber, houseLetter)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(addresses) > 0 {
+			return addresses, nil
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return nil, nil
+}
+
+// AddressesWithinBBox forwards to the first underlying provider that
+// implements `bboxProvider`, or returns `errBBoxUnsupported` if none do. It
+// implements `bboxProvider`.
+func (p *chainProvider) AddressesWithinBBox(ctx context.Context, minLat, minLon, maxLat, maxLon float64) ([]Address, error) {
+	for _, provider := range p.providers {
+		if bp, ok := provider.(bboxProvider); ok {
+			return bp.AddressesWithinBBox(ctx, minLat, minLon, maxLat, maxLon)
+		}
+	}
+
+	return nil, errBBoxUnsupported
+}
+
+// NearestByCoordinates tries each underlying provider in order, returning
+// the first non-nil result.
+func (p *chainProvider) NearestByCoordinates(ctx context.Context, latitude, longitude float64) (*Address, error) {
+	var errs []error
+
+	for _, provider := range p.providers {
+		addr, err := provider.NearestByCoordinates(ctx, latitude, longitude)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if addr != nil {
+			return addr, nil
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"strconv"
+)
+
+// completingProvider wraps an `AddressProvider`, filling in whichever of
+// `Address.Latitude`/`Longitude` or `Address.X`/`Y` the underlying provider
+// didn't populate, so `formatAddress` output is always complete regardless
+// of which provider is selected.
+type completingProvider struct {
+	AddressProvider
+}
+
+// newCompletingProvider wraps upstream so that every returned `Address` has
+// both its WGS84 and Rijksdriehoek coordinates populated.
+func newCompletingProvider(upstream AddressProvider) *completingProvider {
+	return &completingProvider{AddressProvider: upstream}
+}
+
+// LookupByPostalCode implements `AddressProvider`.
+func (p *completingProvider) LookupByPostalCode(ctx context.Context, postalCode, houseNumber, houseLetter string) ([]Address, error) {
+	addresses, err := p.AddressProvider.LookupByPostalCode(ctx, postalCode, houseNumber, houseLetter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range addresses {
+		completeCoordinates(&addresses[i])
+	}
+
+	return addresses, nil
+}
+
+// NearestByCoordinates implements `AddressProvider`.
+func (p *completingProvider) NearestByCoordinates(ctx context.Context, latitude, longitude float64) (*Address, error) {
+	addr, err := p.AddressProvider.NearestByCoordinates(ctx, latitude, longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	if addr != nil {
+		completeCoordinates(addr)
+	}
+
+	return addr, nil
+}
+
+// AddressesWithinBBox forwards to the upstream provider's bounding-box
+// query, if it implements one, returning `errBBoxUnsupported` otherwise so
+// callers can fall back to probing. It implements `bboxProvider`.
+func (p *completingProvider) AddressesWithinBBox(ctx context.Context, minLat, minLon, maxLat, maxLon float64) ([]Address, error) {
+	bp, ok := p.AddressProvider.(bboxProvider)
+	if !ok {
+		return nil, errBBoxUnsupported
+	}
+
+	addresses, err := bp.AddressesWithinBBox(ctx, minLat, minLon, maxLat, maxLon)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range addresses {
+		completeCoordinates(&addresses[i])
+	}
+
+	return addresses, nil
+}
+
+// completeCoordinates fills in whichever coordinate system addr is missing,
+// deriving it from the other.
+func completeCoordinates(addr *Address) {
+	hasWGS84 := addr.Latitude != 0 && addr.Longitude != 0
+	hasRD := addr.X != "" && addr.Y != ""
+
+	switch {
+	case hasRD && !hasWGS84:
+		x, y := parseRDString(addr.X, addr.Y)
+		addr.Latitude, addr.Longitude = RDToWGS84(x, y)
+	case hasWGS84 && !hasRD:
+		x, y := WGS84ToRD(addr.Latitude, addr.Longitude)
+		addr.X = formatRDCoordinate(x)
+		addr.Y = formatRDCoordinate(y)
+	}
+}
+
+// parseRDString parses `Address.X`/`Y` string values back into floats.
+// Malformed values are treated as zero, matching the zero-value handling
+// elsewhere in `Address`.
+func parseRDString(x, y string) (float64, float64) {
+	xf, _ := strconv.ParseFloat(x, 64)
+	yf, _ := strconv.ParseFloat(y, 64)
+	return xf, yf
+}
+
+// formatRDCoordinate formats a Rijksdriehoek coordinate the same way
+// upstream providers do: a whole number of meters.
+func formatRDCoordinate(v float64) string {
+	return strconv.FormatFloat(v, 'f', 0, 64)
+}
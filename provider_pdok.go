@@ -0,0 +1,245 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// pdokBaseURL is the base URL for the official Kadaster/PDOK Locatieserver.
+// See: https://www.pdok.nl/restful-api/-/article/locatieserver
+const pdokBaseURL = "https://api.pdok.nl/bzk/locatieserver/search/v3_1"
+
+// pdokProvider is an `AddressProvider` backed by the PDOK Locatieserver.
+// Unlike `bagservProvider`, it carries BAG identifiers (nummeraanduiding
+// and verblijfsobject IDs) through to `Address.Metadata`.
+type pdokProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// newPDOKProvider constructs an `AddressProvider` that queries the PDOK
+// Locatieserver at baseURL.
+func newPDOKProvider(baseURL string) *pdokProvider {
+	return &pdokProvider{
+		baseURL: baseURL,
+		client: &http.Client{
+			Transport: newInstrumentedTransport("pdok", nil),
+		},
+	}
+}
+
+// pdokResponse mirrors the relevant subset of a Locatieserver `/v3/free` or
+// `/v3/suggest` response.
+type pdokResponse struct {
+	Response struct {
+		NumFound int       `json:"numFound"`
+		Docs     []pdokDoc `json:"docs"`
+	} `json:"response"`
+}
+
+// pdokDoc mirrors a single "adres" document returned by the Locatieserver.
+type pdokDoc struct {
+	ID                    string   `json:"id"`
+	Straatnaam            string   `json:"straatnaam"`
+	Huisnummer            int      `json:"huisnummer"`
+	Huisletter            string   `json:"huisletter"`
+	Huisnummertoevoeging  string   `json:"huisnummertoevoeging"`
+	Postcode              string   `json:"postcode"`
+	Woonplaatsnaam        string   `json:"woonplaatsnaam"`
+	Bouwjaar              int      `json:"bouwjaar"`
+	Oppervlakte           int      `json:"oppervlakte"`
+	Gebruiksdoel          []string `json:"gebruiksdoel"`
+	CentroideLL           string   `json:"centroide_ll"`
+	CentroideRD           string   `json:"centroide_rd"`
+	NummeraanduidingID    string   `json:"nummeraanduiding_id"`
+	AdresseerbaarobjectID string   `json:"adresseerbaarobject_id"`
+}
+
+// LookupByPostalCode looks up addresses by postal code and optional house
+// number and letter, via the Locatieserver `/v3/free` endpoint.
+func (p *pdokProvider) LookupByPostalCode(ctx context.Context, postalCode, houseNumber, houseLetter string) ([]Address, error) {
+	postalCode = strings.ReplaceAll(postalCode, " ", "")
+
+	q := postalCode
+	if houseNumber != "" {
+		q += " " + houseNumber + houseLetter
+	}
+
+	query := url.Values{}
+	query.Set("q", q)
+	query.Set("fq", "type:adres")
+	query.Set("rows", "25")
+
+	docs, err := p.free(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]Address, 0, len(docs))
+	for _, doc := range docs {
+		addresses = append(addresses, doc.toAddress())
+	}
+
+	return addresses, nil
+}
+
+// NearestByCoordinates looks up the nearest address by WGS84 (GPS)
+// coordinates, via the Locatieserver `/v3/free` endpoint, ranked by
+// distance to the given point.
+func (p *pdokProvider) NearestByCoordinates(ctx context.Context, latitude, longitude float64) (*Address, error) {
+	query := url.Values{}
+	query.Set("q", "*")
+	query.Set("fq", "type:adres")
+	query.Set("lat", strconv.FormatFloat(latitude, 'f', -1, 64))
+	query.Set("lon", strconv.FormatFloat(longitude, 'f', -1, 64))
+	query.Set("rows", "1")
+
+	docs, err := p.free(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	addr := docs[0].toAddress()
+
+	return &addr, nil
+}
+
+// AddressesWithinBBox returns every address whose centroid falls within the
+// given WGS84 bounding box. It implements `bboxProvider`.
+func (p *pdokProvider) AddressesWithinBBox(ctx context.Context, minLat, minLon, maxLat, maxLon float64) ([]Address, error) {
+	query := url.Values{}
+	query.Set("q", "*")
+	// Unlike centroide_ll's own WKT representation (POINT(lon lat)), the
+	// Locatieserver's centroide_ll range filter takes lat,lon order.
+	query.Set("fq", fmt.Sprintf("type:adres AND centroide_ll:[%s,%s TO %s,%s]",
+		strconv.FormatFloat(minLat, 'f', -1, 64), strconv.FormatFloat(minLon, 'f', -1, 64),
+		strconv.FormatFloat(maxLat, 'f', -1, 64), strconv.FormatFloat(maxLon, 'f', -1, 64),
+	))
+	query.Set("rows", "100")
+
+	docs, err := p.free(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]Address, 0, len(docs))
+	for _, doc := range docs {
+		addresses = append(addresses, doc.toAddress())
+	}
+
+	return addresses, nil
+}
+
+// free performs a request against the `/v3/free` endpoint with the given
+// query parameters and returns the matching documents.
+func (p *pdokProvider) free(ctx context.Context, query url.Values) ([]pdokDoc, error) {
+	requestURL := fmt.Sprintf("%v/free?%v", p.baseURL, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("PDOK Locatieserver returned status code %d", resp.StatusCode)
+	}
+
+	var body pdokResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	return body.Response.Docs, nil
+}
+
+// toAddress converts a Locatieserver document to an `Address`, carrying its
+// BAG identifiers in `Metadata`.
+func (d pdokDoc) toAddress() Address {
+	addr := Address{
+		Street:        d.Straatnaam,
+		HouseNumber:   d.Huisnummer,
+		HouseLetter:   d.Huisletter,
+		HouseSuffix:   d.Huisnummertoevoeging,
+		City:          d.Woonplaatsnaam,
+		PostalCode:    d.Postcode,
+		Area:          d.Oppervlakte,
+		UsagePurposes: d.Gebruiksdoel,
+		BuildYear:     d.Bouwjaar,
+	}
+
+	// centroide_ll is WKT `POINT(lon lat)`.
+	if lon, lat, ok := parsePoint(d.CentroideLL); ok {
+		addr.Latitude = lat
+		addr.Longitude = lon
+	}
+
+	if x, y, ok := parsePoint(d.CentroideRD); ok {
+		addr.X = strconv.FormatFloat(x, 'f', -1, 64)
+		addr.Y = strconv.FormatFloat(y, 'f', -1, 64)
+	}
+
+	if d.NummeraanduidingID != "" || d.AdresseerbaarobjectID != "" {
+		addr.Metadata = map[string]string{}
+		if d.NummeraanduidingID != "" {
+			addr.Metadata["bagNummeraanduidingId"] = d.NummeraanduidingID
+		}
+		if d.AdresseerbaarobjectID != "" {
+			addr.Metadata["bagVerblijfsobjectId"] = d.AdresseerbaarobjectID
+		}
+	}
+
+	return addr
+}
+
+// parsePoint parses a WKT `POINT(x y)` string, as returned by the
+// Locatieserver's `centroide_ll` and `centroide_rd` fields.
+func parsePoint(wkt string) (x, y float64, ok bool) {
+	wkt = strings.TrimPrefix(wkt, "POINT(")
+	wkt = strings.TrimSuffix(wkt, ")")
+
+	parts := strings.Fields(wkt)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	x, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	y, err = strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return x, y, true
+}
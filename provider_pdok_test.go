@@ -0,0 +1,51 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestPDOKProviderAddressesWithinBBoxAxisOrder guards against the
+// centroide_ll range filter's axis order silently flipping back to
+// lon,lat: the field's own WKT representation is POINT(lon lat), but the
+// Locatieserver's range filter expects lat,lon.
+func TestPDOKProviderAddressesWithinBBoxAxisOrder(t *testing.T) {
+	var gotQuery url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"response":{"numFound":0,"docs":[]}}`))
+	}))
+	defer server.Close()
+
+	p := newPDOKProvider(server.URL)
+
+	const minLat, minLon, maxLat, maxLon = 52.0, 5.0, 52.1, 5.1
+
+	if _, err := p.AddressesWithinBBox(context.Background(), minLat, minLon, maxLat, maxLon); err != nil {
+		t.Fatalf("AddressesWithinBBox returned error: %v", err)
+	}
+
+	wantFQ := "type:adres AND centroide_ll:[52,5 TO 52.1,5.1]"
+	if gotFQ := gotQuery.Get("fq"); gotFQ != wantFQ {
+		t.Errorf("fq = %q, want %q", gotFQ, wantFQ)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "math"
+
+// rdX0, rdY0 are the Rijksdriehoek (RD, EPSG:28992) coordinates of the
+// Amersfoort base point.
+const (
+	rdX0 = 155000.00
+	rdY0 = 463000.00
+)
+
+// wgs84Phi0, wgs84Lambda0 are the WGS84 latitude/longitude of the
+// Amersfoort base point.
+const (
+	wgs84Phi0    = 52.15517440
+	wgs84Lambda0 = 5.38720621
+)
+
+// rdTerm is a single term of an `Rpq`/`Spq`/`Kpq`/`Lpq` polynomial: its
+// coefficient and the powers of the two input deltas it multiplies.
+type rdTerm struct {
+	p, q  float64
+	coeff float64
+}
+
+// rpqTerms approximate the RD X coordinate from Δφ (p) and Δλ (q), in units
+// of 0.36″×10⁴, relative to the Amersfoort base point.
+// See: https://www.nsgi.nl (Kadaster's "Transformatieformules tussen RD en WGS84").
+var rpqTerms = []rdTerm{
+	{1, 0, -0.705},
+	{0, 1, 190094.945},
+	{1, 1, -11832.228},
+	{2, 1, -114.221},
+	{0, 3, -32.391},
+	{3, 1, -2.340},
+	{1, 3, -0.608},
+	{0, 2, -0.008},
+	{2, 3, 0.148},
+}
+
+// spqTerms approximate the RD Y coordinate from Δφ (p) and Δλ (q), in the
+// same units as rpqTerms.
+var spqTerms = []rdTerm{
+	{1, 0, 309056.544},
+	{0, 2, 3638.893},
+	{2, 0, 73.077},
+	{1, 2, -157.984},
+	{3, 0, 59.788},
+	{0, 1, 0.433},
+	{2, 2, -6.439},
+	{1, 1, -0.032},
+	{0, 4, 0.092},
+	{1, 4, -0.054},
+}
+
+// kpqTerms approximate the WGS84 latitude correction (in arcseconds) from
+// ΔX (p) and ΔY (q), each scaled by 10⁻⁵.
+var kpqTerms = []rdTerm{
+	{1, 0, -0.00738},
+	{0, 1, 3235.65389},
+	{2, 0, -32.58297},
+	{0, 2, -0.24750},
+	{2, 1, -0.84978},
+	{0, 3, -0.06550},
+	{2, 2, -0.01709},
+	{4, 0, 0.00530},
+	{2, 3, -0.00039},
+	{4, 1, 0.00033},
+	{1, 1, -0.00012},
+}
+
+// lpqTerms approximate the WGS84 longitude correction (in arcseconds) from
+// ΔX (p) and ΔY (q), each scaled by 10⁻⁵.
+var lpqTerms = []rdTerm{
+	{1, 0, 5260.52916},
+	{1, 1, 105.94684},
+	{1, 2, 2.45656},
+	{3, 0, -0.81885},
+	{1, 3, 0.05594},
+	{3, 1, -0.05607},
+	{0, 1, 0.01199},
+	{3, 2, -0.00256},
+	{1, 4, 0.00128},
+	{0, 2, 0.00022},
+	{2, 0, -0.00022},
+	{5, 0, 0.00026},
+}
+
+// evalPoly evaluates Σ term.coeff·a^term.p·b^term.q over terms.
+func evalPoly(terms []rdTerm, a, b float64) float64 {
+	var sum float64
+	for _, t := range terms {
+		sum += t.coeff * math.Pow(a, t.p) * math.Pow(b, t.q)
+	}
+	return sum
+}
+
+// WGS84ToRD converts a WGS84 (latitude, longitude) pair to Rijksdriehoek
+// (X, Y) coordinates, using the Kadaster approximation formula. It is only
+// valid for coordinates within (roughly) the Netherlands.
+func WGS84ToRD(latitude, longitude float64) (x, y float64) {
+	dPhi := 0.36 * (latitude - wgs84Phi0)
+	dLambda := 0.36 * (longitude - wgs84Lambda0)
+
+	x = rdX0 + evalPoly(rpqTerms, dPhi, dLambda)
+	y = rdY0 + evalPoly(spqTerms, dPhi, dLambda)
+
+	return x, y
+}
+
+// RDToWGS84 converts Rijksdriehoek (X, Y) coordinates to a WGS84 (latitude,
+// longitude) pair, using the Kadaster approximation formula. It is only
+// valid for coordinates within (roughly) the Netherlands.
+func RDToWGS84(x, y float64) (latitude, longitude float64) {
+	dX := (x - rdX0) * 1e-5
+	dY := (y - rdY0) * 1e-5
+
+	latitude = wgs84Phi0 + evalPoly(kpqTerms, dX, dY)/3600
+	longitude = wgs84Lambda0 + evalPoly(lpqTerms, dX, dY)/3600
+
+	return latitude, longitude
+}
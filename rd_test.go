@@ -0,0 +1,86 @@
+// Copyright 2025 David Stotijn
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+// TestWGS84ToRDBasePoint pins the Amersfoort base point identity: since the
+// approximation polynomial is defined relative to this point, converting it
+// must reproduce (rdX0, rdY0) exactly (Δφ=Δλ=0, so every term vanishes). A
+// stray constant term in rpqTerms/spqTerms would silently reintroduce a
+// fixed offset into every conversion, as happened in bb0f91e/b497b6f.
+func TestWGS84ToRDBasePoint(t *testing.T) {
+	x, y := WGS84ToRD(wgs84Phi0, wgs84Lambda0)
+
+	if x != rdX0 {
+		t.Errorf("X = %v, want %v", x, rdX0)
+	}
+	if y != rdY0 {
+		t.Errorf("Y = %v, want %v", y, rdY0)
+	}
+}
+
+// TestWGS84ToRDKnownPoint pins a known real-world reference pair (the
+// Westertoren, Amsterdam) against its published RD coordinates, to within
+// the approximation formula's documented accuracy (~centimeters).
+func TestWGS84ToRDKnownPoint(t *testing.T) {
+	const (
+		lat, lon = 52.37453253, 4.88352559
+		wantX    = 120700.723
+		wantY    = 487525.501
+		epsilon  = 0.01
+	)
+
+	x, y := WGS84ToRD(lat, lon)
+
+	if diff := x - wantX; diff < -epsilon || diff > epsilon {
+		t.Errorf("X = %v, want within %v of %v", x, epsilon, wantX)
+	}
+	if diff := y - wantY; diff < -epsilon || diff > epsilon {
+		t.Errorf("Y = %v, want within %v of %v", y, epsilon, wantY)
+	}
+}
+
+// TestRDToWGS84BasePoint pins the inverse conversion's base point identity:
+// converting (rdX0, rdY0) must reproduce (wgs84Phi0, wgs84Lambda0) exactly.
+func TestRDToWGS84BasePoint(t *testing.T) {
+	lat, lon := RDToWGS84(rdX0, rdY0)
+
+	if lat != wgs84Phi0 {
+		t.Errorf("latitude = %v, want %v", lat, wgs84Phi0)
+	}
+	if lon != wgs84Lambda0 {
+		t.Errorf("longitude = %v, want %v", lon, wgs84Lambda0)
+	}
+}
+
+// TestRDToWGS84RoundTrip checks that converting a known point to RD and
+// back recovers the original WGS84 coordinates, within a small tolerance.
+func TestRDToWGS84RoundTrip(t *testing.T) {
+	const (
+		wantLat, wantLon = 52.373056, 4.893056
+		epsilon          = 1e-4
+	)
+
+	x, y := WGS84ToRD(wantLat, wantLon)
+	lat, lon := RDToWGS84(x, y)
+
+	if diff := lat - wantLat; diff < -epsilon || diff > epsilon {
+		t.Errorf("latitude = %v, want within %v of %v", lat, epsilon, wantLat)
+	}
+	if diff := lon - wantLon; diff < -epsilon || diff > epsilon {
+		t.Errorf("longitude = %v, want within %v of %v", lon, epsilon, wantLon)
+	}
+}